@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/garagesale/internal/platform/auth"
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+func cmdGentoken(args []string) error {
+	fs := flag.NewFlagSet("gentoken", flag.ExitOnError)
+	userID := fs.String("user-id", "", "subject (user id) to embed in the token")
+	keyFile := fs.String("key-file", "private.pem", "PEM-encoded RSA private key to sign the token with")
+	keyID := fs.String("key-id", "1", "kid to embed in the token header")
+	alg := fs.String("alg", "RS256", "JWT signing algorithm")
+	roles := fs.String("roles", auth.RoleUser, "comma-separated list of roles to grant")
+	ttl := fs.Duration("ttl", 365*24*time.Hour, "how long the token should be valid for")
+	fs.Parse(args)
+
+	if *userID == "" {
+		return errors.New("-user-id is required")
+	}
+
+	keyContents, err := ioutil.ReadFile(*keyFile)
+	if err != nil {
+		return errors.Wrap(err, "reading key file")
+	}
+
+	block, _ := pem.Decode(keyContents)
+	if block == nil {
+		return errors.New("decoding PEM block from key file")
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "parsing private key")
+	}
+
+	authenticator, err := auth.NewAuthenticator(privateKey, *keyID, *alg)
+	if err != nil {
+		return errors.Wrap(err, "constructing authenticator")
+	}
+
+	now := time.Now()
+	claims := auth.Claims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   *userID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(*ttl).Unix(),
+		},
+		Roles: strings.Split(*roles, ","),
+	}
+
+	tkn, err := authenticator.GenerateToken(claims)
+	if err != nil {
+		return errors.Wrap(err, "generating token")
+	}
+
+	fmt.Println(tkn)
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+func cmdKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := fs.String("out", "private.pem", "file to write the generated PEM-encoded RSA private key to")
+	bits := fs.Int("bits", 2048, "key size in bits")
+	fs.Parse(args)
+
+	key, err := rsa.GenerateKey(rand.Reader, *bits)
+	if err != nil {
+		return errors.Wrap(err, "generating key")
+	}
+
+	block := &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}
+
+	if err := ioutil.WriteFile(*out, pem.EncodeToMemory(block), 0600); err != nil {
+		return errors.Wrap(err, "writing key file")
+	}
+
+	return nil
+}
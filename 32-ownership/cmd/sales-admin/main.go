@@ -0,0 +1,61 @@
+// Command sales-admin bootstraps and maintains a sales-api deployment:
+// running schema migrations, seeding fixture data, and minting the RSA
+// keys and JWTs the API uses for authentication.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// name is this application's name, used for flag/usage output.
+const name = "sales-admin"
+
+// dbConfigName is the envconfig prefix cmdMigrate and cmdSeed parse their
+// database.Config with. It deliberately matches cmd/sales-api's own prefix
+// (its const name) rather than sales-admin's, so this binary manages
+// schema/fixtures for the same database sales-api connects to, reading the
+// same DB_* environment variables.
+const dbConfigName = "sales-api"
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	cmd, args := os.Args[1], os.Args[2:]
+
+	var err error
+	switch cmd {
+	case "migrate":
+		err = cmdMigrate(args)
+	case "seed":
+		err = cmdSeed(args)
+	case "keygen":
+		err = cmdKeygen(args)
+	case "gentoken":
+		err = cmdGentoken(args)
+	default:
+		usage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s: %s\n", cmd, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `usage: sales-admin <command> [flags]
+
+commands:
+  migrate              run schema migrations against DB
+  seed                 insert a canned product/sales dataset
+  keygen               generate an RSA private key PEM
+  gentoken             mint a JWT for a user
+
+Run "sales-admin <command> -h" for flags specific to a command.
+`)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/ardanlabs/garagesale/internal/platform/database"
+	"github.com/ardanlabs/garagesale/internal/schema"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+)
+
+func cmdMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Parse(args)
+
+	var cfg struct {
+		DB database.Config
+	}
+	if err := envconfig.Process(dbConfigName, &cfg); err != nil {
+		return errors.Wrap(err, "parsing config")
+	}
+
+	db, err := database.Open(cfg.DB)
+	if err != nil {
+		return errors.Wrap(err, "connecting to db")
+	}
+	defer db.Close()
+
+	if err := schema.Migrate(db); err != nil {
+		return errors.Wrap(err, "applying migrations")
+	}
+
+	return nil
+}
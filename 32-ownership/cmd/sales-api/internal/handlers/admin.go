@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ardanlabs/garagesale/internal/platform/log"
+	"github.com/ardanlabs/garagesale/internal/platform/readonly"
+	"github.com/ardanlabs/garagesale/internal/platform/web"
+	"github.com/pkg/errors"
+)
+
+// Admin defines handlers for operator-facing endpoints that are not part
+// of the public product catalog API.
+type Admin struct{}
+
+// NewAdmin constructs an Admin handler set.
+func NewAdmin() *Admin {
+	return &Admin{}
+}
+
+type setReadOnlyRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SetReadOnly toggles read-only mode for the whole service. It is guarded
+// by mid.HasRole(auth.RoleAdmin) at the route level.
+func (a *Admin) SetReadOnly(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	var req setReadOnlyRequest
+	if err := web.Decode(r, &req); err != nil {
+		return errors.Wrap(err, "decoding read-only request")
+	}
+
+	readonly.SetEnabled(req.Enabled)
+
+	log.FromContext(ctx).Infof("read-only mode set to %t", req.Enabled)
+
+	return web.Respond(ctx, w, setReadOnlyRequest{Enabled: req.Enabled}, http.StatusOK)
+}
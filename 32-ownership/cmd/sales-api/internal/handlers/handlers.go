@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ardanlabs/garagesale/internal/mid"
+	"github.com/ardanlabs/garagesale/internal/platform/auth"
+	"github.com/ardanlabs/garagesale/internal/platform/log"
+	"github.com/ardanlabs/garagesale/internal/platform/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// API constructs an http.Handler exposing all of the routes for the sales
+// service, wiring the given db, logger and authenticator into each handler
+// and running tracing, metrics, error handling, authentication and logging
+// as middleware on every request. mid.Errors sits just outside Authenticate
+// so it catches auth/role/read-only errors too, and translates them to a
+// response before control returns to Metrics/Trace, so those see the real
+// status code rather than the zero value. mid.Logger sits just inside
+// Authenticate so the request log it emits already has claims on ctx and
+// can include the caller's user id. maxPageSize caps the page size List and
+// ListSales honor, regardless of what a client requests.
+func API(db *sqlx.DB, logger *log.Logger, authenticator *auth.Authenticator, maxPageSize int) http.Handler {
+	app := web.NewApp(mid.Trace(), mid.Metrics(), mid.Errors(), mid.Authenticate(authenticator), mid.Logger(logger))
+
+	p := New(db, maxPageSize)
+	a := NewAdmin()
+
+	app.Handle(http.MethodGet, "/v1/products", p.List)
+	app.Handle(http.MethodPost, "/v1/products", p.Create, mid.HasRole(auth.RoleAdmin), mid.ReadOnly())
+	app.Handle(http.MethodGet, "/v1/products/{id}", p.Get)
+	app.Handle(http.MethodPut, "/v1/products/{id}", p.Update, mid.HasRole(auth.RoleAdmin), mid.ReadOnly())
+	app.Handle(http.MethodDelete, "/v1/products/{id}", p.Delete, mid.ReadOnly())
+	app.Handle(http.MethodPost, "/v1/products/{id}/sales", p.AddSale, mid.ReadOnly())
+	app.Handle(http.MethodGet, "/v1/products/{id}/sales", p.ListSales)
+
+	app.Handle(http.MethodPost, "/v1/admin/readonly", a.SetReadOnly, mid.HasRole(auth.RoleAdmin))
+
+	return app
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/ardanlabs/garagesale/internal/products"
+	"github.com/pkg/errors"
+)
+
+const defaultPageSize = 20
+
+// productSortFields whitelists the query-string sort values accepted by
+// List, mapping each to the exported products.Product field it orders by.
+// Any sort value not in this map is rejected rather than interpolated into
+// an ORDER BY clause.
+var productSortFields = map[string]string{
+	"product_id":   "ID",
+	"name":         "Name",
+	"cost":         "Cost",
+	"date_created": "DateCreated",
+}
+
+// saleSortFields is the equivalent whitelist for ListSales.
+var saleSortFields = map[string]string{
+	"sale_id":      "ID",
+	"date_created": "DateCreated",
+}
+
+// pageCursor is the decoded form of the opaque ?cursor= query parameter.
+type pageCursor struct {
+	LastID        string      `json:"last_id"`
+	LastSortValue interface{} `json:"last_sort_value"`
+}
+
+func encodeCursor(c pageCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.Wrap(err, "encoding cursor")
+	}
+	return base64.URLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var c pageCursor
+	if s == "" {
+		return c, nil
+	}
+
+	b, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return pageCursor{}, errors.Wrap(err, "decoding cursor")
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return pageCursor{}, errors.Wrap(err, "unmarshalling cursor")
+	}
+
+	return c, nil
+}
+
+// parseListParams parses the ?limit=&cursor=&sort=&name=&min_cost=&max_cost=
+// query parameters shared by List and ListSales into a products.ListParams,
+// validating sort against allowedSort so client input never reaches the
+// ORDER BY clause directly. limit is capped at maxPageSize, which callers
+// source from the service's configuration rather than a hardcoded value.
+func parseListParams(r *http.Request, allowedSort map[string]string, maxPageSize int) (products.ListParams, error) {
+	q := r.URL.Query()
+
+	limit := defaultPageSize
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return products.ListParams{}, errors.New("invalid limit")
+		}
+		limit = n
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	sort := q.Get("sort")
+	if sort == "" {
+		// Default to the allowlist's ID key (product_id for List, sale_id
+		// for ListSales).
+		for k, field := range allowedSort {
+			if field == "ID" {
+				sort = k
+			}
+		}
+	}
+	if _, ok := allowedSort[sort]; !ok {
+		return products.ListParams{}, errors.Errorf("invalid sort field %q", sort)
+	}
+
+	c, err := decodeCursor(q.Get("cursor"))
+	if err != nil {
+		return products.ListParams{}, err
+	}
+
+	params := products.ListParams{
+		Limit:         limit,
+		Sort:          sort,
+		LastID:        c.LastID,
+		LastSortValue: c.LastSortValue,
+		Name:          q.Get("name"),
+	}
+
+	if raw := q.Get("min_cost"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return products.ListParams{}, errors.New("invalid min_cost")
+		}
+		params.MinCost = &v
+	}
+	if raw := q.Get("max_cost"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return products.ListParams{}, errors.New("invalid max_cost")
+		}
+		params.MaxCost = &v
+	}
+
+	return params, nil
+}
+
+// nextCursor builds the opaque cursor pointing past last, the final item of
+// a page. It always encodes a cursor; callers decide whether a page is full
+// enough to warrant one.
+func nextCursor(last interface{}, id, sort string, allowedSort map[string]string) (string, error) {
+	field, ok := allowedSort[sort]
+	if !ok {
+		return "", errors.Errorf("invalid sort field %q", sort)
+	}
+
+	v := reflect.ValueOf(last)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	return encodeCursor(pageCursor{
+		LastID:        id,
+		LastSortValue: v.FieldByName(field).Interface(),
+	})
+}
+
+// listEnvelope is the response body shape for paginated list endpoints.
+type listEnvelope struct {
+	Data          interface{} `json:"data"`
+	NextCursor    string      `json:"next_cursor,omitempty"`
+	TotalEstimate int         `json:"total_estimate"`
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := pageCursor{LastID: "a2b0639f-2cc6-44b8-b97b-15d69dbb511e", LastSortValue: "Comic Books"}
+
+	enc, err := encodeCursor(want)
+	if err != nil {
+		t.Fatalf("encodeCursor: %s", err)
+	}
+
+	got, err := decodeCursor(enc)
+	if err != nil {
+		t.Fatalf("decodeCursor: %s", err)
+	}
+	if got != want {
+		t.Fatalf("decodeCursor(%q) = %+v; want %+v", enc, got, want)
+	}
+}
+
+func TestDecodeCursorEmpty(t *testing.T) {
+	got, err := decodeCursor("")
+	if err != nil {
+		t.Fatalf("decodeCursor(\"\"): %s", err)
+	}
+	if got != (pageCursor{}) {
+		t.Fatalf("decodeCursor(\"\") = %+v; want zero value", got)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	if _, err := decodeCursor("not-valid-base64!!!"); err == nil {
+		t.Fatal("expected an error decoding a malformed cursor")
+	}
+}
+
+func TestParseListParamsRejectsUnknownSort(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/products?sort=user_id", nil)
+
+	if _, err := parseListParams(r, productSortFields, 100); err == nil {
+		t.Fatal("expected an error for a sort field not in the whitelist")
+	}
+}
+
+func TestParseListParamsCapsLimitAtMaxPageSize(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/products?limit=500", nil)
+
+	params, err := parseListParams(r, productSortFields, 100)
+	if err != nil {
+		t.Fatalf("parseListParams: %s", err)
+	}
+	if params.Limit != 100 {
+		t.Fatalf("Limit = %d; want it capped at 100", params.Limit)
+	}
+}
+
+func TestParseListParamsDefaultsSort(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/products", nil)
+
+	params, err := parseListParams(r, productSortFields, 100)
+	if err != nil {
+		t.Fatalf("parseListParams: %s", err)
+	}
+	if params.Sort != "product_id" {
+		t.Fatalf("Sort = %q; want default %q", params.Sort, "product_id")
+	}
+}
@@ -2,33 +2,69 @@ package handlers
 
 import (
 	"context"
-	"log"
 	"net/http"
 	"time"
 
 	"github.com/ardanlabs/garagesale/internal/platform/auth"
+	"github.com/ardanlabs/garagesale/internal/platform/log"
 	"github.com/ardanlabs/garagesale/internal/platform/web"
 	"github.com/ardanlabs/garagesale/internal/products"
 	"github.com/go-chi/chi"
 	"github.com/jmoiron/sqlx"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"github.com/pkg/errors"
 )
 
 // Products defines all of the handlers related to products. It holds the
 // application state needed by the handler methods.
 type Products struct {
-	db  *sqlx.DB
-	log *log.Logger
+	db          *sqlx.DB
+	maxPageSize int
 }
 
-// List gets all products from the service layer.
+// New constructs a Products handler set backed by db. maxPageSize caps the
+// page size List/ListSales honor regardless of what a client requests.
+func New(db *sqlx.DB, maxPageSize int) *Products {
+	return &Products{db: db, maxPageSize: maxPageSize}
+}
+
+// dbSpan starts a child span for a call into the products service layer,
+// tagged so traces make it clear which SQL-backed operation ran.
+func dbSpan(ctx context.Context, op, statement string) (opentracing.Span, context.Context) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, op)
+	span.SetTag("db.statement", statement)
+	return span, ctx
+}
+
+// List gets a page of products from the service layer, filtered and sorted
+// according to the request's query string.
 func (s *Products) List(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-	list, err := products.List(ctx, s.db)
+	params, err := parseListParams(r, productSortFields, s.maxPageSize)
+	if err != nil {
+		return web.WrapErrorWithStatus(err, http.StatusBadRequest)
+	}
+
+	span, ctx := dbSpan(ctx, "products.List", "SELECT * FROM products")
+	defer span.Finish()
+
+	list, total, err := products.List(ctx, s.db, params)
 	if err != nil {
+		ext.Error.Set(span, true)
 		return errors.Wrap(err, "getting product list")
 	}
 
-	return web.Respond(ctx, w, list, http.StatusOK)
+	var next string
+	if len(list) == params.Limit {
+		last := list[len(list)-1]
+		if next, err = nextCursor(last, last.ID, params.Sort, productSortFields); err != nil {
+			return errors.Wrap(err, "building next cursor")
+		}
+	}
+
+	log.FromContext(ctx).Infof("listed %d products", len(list))
+
+	return web.Respond(ctx, w, listEnvelope{Data: list, NextCursor: next, TotalEstimate: total}, http.StatusOK)
 }
 
 // Create decodes the body of a request to create a new product. The full
@@ -44,10 +80,17 @@ func (s *Products) Create(ctx context.Context, w http.ResponseWriter, r *http.Re
 		return errors.New("claims missing from context")
 	}
 
+	span, ctx := dbSpan(ctx, "products.Create", "INSERT INTO products")
+	defer span.Finish()
+
 	p, err := products.Create(ctx, s.db, claims, np, time.Now())
 	if err != nil {
+		ext.Error.Set(span, true)
 		return errors.Wrap(err, "creating new product")
 	}
+	span.SetTag("product.id", p.ID)
+
+	log.FromContext(ctx).Infof("created product %s", p.ID)
 
 	return web.Respond(ctx, w, &p, http.StatusCreated)
 }
@@ -56,8 +99,13 @@ func (s *Products) Create(ctx context.Context, w http.ResponseWriter, r *http.Re
 func (s *Products) Get(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	id := chi.URLParam(r, "id")
 
+	span, ctx := dbSpan(ctx, "products.Get", "SELECT * FROM products WHERE product_id = $1")
+	defer span.Finish()
+	span.SetTag("product.id", id)
+
 	p, err := products.Get(ctx, s.db, id)
 	if err != nil {
+		ext.Error.Set(span, true)
 		switch err {
 		case products.ErrNotFound:
 			return web.WrapErrorWithStatus(err, http.StatusNotFound)
@@ -68,6 +116,8 @@ func (s *Products) Get(ctx context.Context, w http.ResponseWriter, r *http.Reque
 		}
 	}
 
+	log.FromContext(ctx).Infof("fetched product %s", id)
+
 	return web.Respond(ctx, w, p, http.StatusOK)
 }
 
@@ -86,7 +136,12 @@ func (s *Products) Update(ctx context.Context, w http.ResponseWriter, r *http.Re
 		return errors.New("claims missing from context")
 	}
 
+	span, ctx := dbSpan(ctx, "products.Update", "UPDATE products")
+	defer span.Finish()
+	span.SetTag("product.id", id)
+
 	if err := products.Update(ctx, s.db, claims, id, update, time.Now()); err != nil {
+		ext.Error.Set(span, true)
 		switch err {
 		case products.ErrNotFound:
 			return web.WrapErrorWithStatus(err, http.StatusNotFound)
@@ -99,6 +154,8 @@ func (s *Products) Update(ctx context.Context, w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	log.FromContext(ctx).Infof("updated product %s", id)
+
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
 }
 
@@ -106,7 +163,12 @@ func (s *Products) Update(ctx context.Context, w http.ResponseWriter, r *http.Re
 func (s *Products) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	id := chi.URLParam(r, "id")
 
+	span, ctx := dbSpan(ctx, "products.Delete", "DELETE FROM products WHERE product_id = $1")
+	defer span.Finish()
+	span.SetTag("product.id", id)
+
 	if err := products.Delete(ctx, s.db, id); err != nil {
+		ext.Error.Set(span, true)
 		switch err {
 		case products.ErrInvalidID:
 			return web.WrapErrorWithStatus(err, http.StatusBadRequest)
@@ -115,6 +177,8 @@ func (s *Products) Delete(ctx context.Context, w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	log.FromContext(ctx).Infof("deleted product %s", id)
+
 	return web.Respond(ctx, w, nil, http.StatusNoContent)
 }
 
@@ -128,22 +192,50 @@ func (s *Products) AddSale(ctx context.Context, w http.ResponseWriter, r *http.R
 
 	productID := chi.URLParam(r, "id")
 
+	span, ctx := dbSpan(ctx, "products.AddSale", "INSERT INTO sales")
+	defer span.Finish()
+	span.SetTag("product.id", productID)
+
 	sale, err := products.AddSale(ctx, s.db, ns, productID, time.Now())
 	if err != nil {
+		ext.Error.Set(span, true)
 		return errors.Wrap(err, "adding new sale")
 	}
 
+	log.FromContext(ctx).Infof("added sale %s for product %s", sale.ID, productID)
+
 	return web.Respond(ctx, w, sale, http.StatusCreated)
 }
 
-// ListSales gets all sales for a particular product.
+// ListSales gets a page of sales for a particular product, filtered and
+// sorted according to the request's query string.
 func (s *Products) ListSales(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	id := chi.URLParam(r, "id")
 
-	list, err := products.ListSales(ctx, s.db, id)
+	params, err := parseListParams(r, saleSortFields, s.maxPageSize)
 	if err != nil {
+		return web.WrapErrorWithStatus(err, http.StatusBadRequest)
+	}
+
+	span, ctx := dbSpan(ctx, "products.ListSales", "SELECT * FROM sales WHERE product_id = $1")
+	defer span.Finish()
+	span.SetTag("product.id", id)
+
+	list, total, err := products.ListSales(ctx, s.db, id, params)
+	if err != nil {
+		ext.Error.Set(span, true)
 		return errors.Wrap(err, "getting sales list")
 	}
 
-	return web.Respond(ctx, w, list, http.StatusOK)
+	var next string
+	if len(list) == params.Limit {
+		last := list[len(list)-1]
+		if next, err = nextCursor(last, last.ID, params.Sort, saleSortFields); err != nil {
+			return errors.Wrap(err, "building next cursor")
+		}
+	}
+
+	log.FromContext(ctx).Infof("listed %d sales for product %s", len(list), id)
+
+	return web.Respond(ctx, w, listEnvelope{Data: list, NextCursor: next, TotalEstimate: total}, http.StatusOK)
 }
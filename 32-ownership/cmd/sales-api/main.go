@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ardanlabs/garagesale/cmd/sales-api/internal/handlers"
+	"github.com/ardanlabs/garagesale/internal/platform/auth"
+	"github.com/ardanlabs/garagesale/internal/platform/database"
+	"github.com/ardanlabs/garagesale/internal/platform/debug"
+	"github.com/ardanlabs/garagesale/internal/platform/log"
+	"github.com/ardanlabs/garagesale/internal/platform/readonly"
+	"github.com/ardanlabs/garagesale/internal/platform/trace"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+)
+
+// This is the application name.
+const name = "sales-api"
+
+type config struct {
+	DB database.Config
+
+	HTTP struct {
+		Address      string `default:":8000"`
+		DebugAddress string `default:":6060" envconfig:"debug_address"`
+	}
+
+	Log struct {
+		Level  string `default:"info"`
+		Format string `default:"json" envconfig:"format"`
+	}
+
+	Trace struct {
+		ServiceName string  `default:"sales-api" envconfig:"service_name"`
+		Endpoint    string  `default:"http://localhost:14268/api/traces"`
+		SampleRate  float64 `default:"1" envconfig:"sample_rate"`
+	}
+
+	Auth struct {
+		KeyFile   string `default:"private.pem" envconfig:"key_file"`
+		KeyID     string `default:"1" envconfig:"key_id"`
+		Algorithm string `default:"RS256"`
+
+		OIDC struct {
+			IssuerURL  string `envconfig:"issuer_url"`
+			ClientID   string `envconfig:"client_id"`
+			RolesClaim string `default:"roles" envconfig:"roles_claim"`
+		}
+	}
+
+	ReadOnly struct {
+		Enabled bool `default:"false" envconfig:"enabled"`
+	}
+
+	Pagination struct {
+		MaxPageSize int `default:"100" envconfig:"max_page_size"`
+	}
+}
+
+// createAuthenticator loads the local signing key and, if cfg.Auth.OIDC is
+// configured, registers that provider too so tokens from either source are
+// accepted.
+func createAuthenticator(cfg config) (*auth.Authenticator, error) {
+	keyContents, err := ioutil.ReadFile(cfg.Auth.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading auth private key")
+	}
+
+	block, _ := pem.Decode(keyContents)
+	if block == nil {
+		return nil, errors.New("decoding PEM block from key file")
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing private key")
+	}
+
+	authenticator, err := auth.NewAuthenticator(privateKey, cfg.Auth.KeyID, cfg.Auth.Algorithm)
+	if err != nil {
+		return nil, errors.Wrap(err, "constructing authenticator")
+	}
+
+	if cfg.Auth.OIDC.IssuerURL != "" {
+		oidcCfg := auth.OIDCConfig{
+			IssuerURL:  cfg.Auth.OIDC.IssuerURL,
+			ClientID:   cfg.Auth.OIDC.ClientID,
+			RolesClaim: cfg.Auth.OIDC.RolesClaim,
+		}
+		if err := authenticator.AddOIDCProvider(oidcCfg); err != nil {
+			return nil, errors.Wrap(err, "adding oidc provider")
+		}
+	}
+
+	return authenticator, nil
+}
+
+func main() {
+	// Process inputs.
+	var flags struct {
+		configOnly bool
+	}
+	flag.Usage = func() {
+		fmt.Print("This daemon is a service which manages products.\n\nUsage of sales-api:\n\nsales-api [flags]\n\n")
+		flag.CommandLine.SetOutput(os.Stdout)
+		flag.PrintDefaults()
+		fmt.Print("\nConfiguration:\n\n")
+		envconfig.Usage(name, &config{})
+	}
+	flag.BoolVar(&flags.configOnly, "config-only", false, "only show parsed configuration and exit")
+	flag.Parse()
+
+	var cfg config
+	if err := envconfig.Process(name, &cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: parsing config: %s\n", err)
+		os.Exit(1)
+	}
+
+	if flags.configOnly {
+		if err := json.NewEncoder(os.Stdout).Encode(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "error: encoding config as json: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	logger, err := log.New(log.Config{Level: cfg.Log.Level, Format: cfg.Log.Format}, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: constructing logger: %s\n", err)
+		os.Exit(1)
+	}
+
+	_, closer, err := trace.New(trace.Config{
+		ServiceName: cfg.Trace.ServiceName,
+		Endpoint:    cfg.Trace.Endpoint,
+		SampleRate:  cfg.Trace.SampleRate,
+	})
+	if err != nil {
+		logger.Errorf("constructing tracer: %s", err)
+		os.Exit(1)
+	}
+	defer closer.Close()
+
+	authenticator, err := createAuthenticator(cfg)
+	if err != nil {
+		logger.Errorf("constructing authenticator: %s", err)
+		os.Exit(1)
+	}
+
+	readonly.SetEnabled(cfg.ReadOnly.Enabled)
+
+	db, err := database.Open(cfg.DB)
+	if err != nil {
+		logger.Errorf("connecting to db: %s", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	// ready flips to 0 as soon as shutdown begins, so /readiness starts
+	// failing before the main server stops accepting connections.
+	var ready int32
+	atomic.StoreInt32(&ready, 1)
+
+	debugServer := http.Server{
+		Addr:    cfg.HTTP.DebugAddress,
+		Handler: debug.Mux(db, &ready),
+	}
+	go func() {
+		if err := debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("listening and serving debug endpoints: %s", err)
+		}
+	}()
+
+	server := http.Server{
+		Addr:    cfg.HTTP.Address,
+		Handler: handlers.API(db, logger, authenticator, cfg.Pagination.MaxPageSize),
+	}
+
+	serverErrors := make(chan error, 1)
+	go func() {
+		serverErrors <- server.ListenAndServe()
+	}()
+
+	osSignals := make(chan os.Signal, 1)
+	signal.Notify(osSignals, os.Interrupt, syscall.SIGTERM)
+
+	logger.Infof("startup complete")
+
+	select {
+	case err := <-serverErrors:
+		logger.Errorf("listening and serving: %s", err)
+		os.Exit(1)
+
+	case <-osSignals:
+		logger.Infof("caught signal, shutting down")
+
+		atomic.StoreInt32(&ready, 0)
+
+		// Give outstanding requests 15 seconds to complete.
+		const timeout = 15 * time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Errorf("gracefully shutting down server: %s", err)
+			if err := server.Close(); err != nil {
+				logger.Errorf("closing server: %s", err)
+			}
+		}
+
+		if err := debugServer.Shutdown(ctx); err != nil {
+			logger.Errorf("gracefully shutting down debug server: %s", err)
+		}
+	}
+
+	logger.Infof("done")
+}
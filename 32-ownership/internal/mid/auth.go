@@ -0,0 +1,53 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/ardanlabs/garagesale/internal/platform/auth"
+	"github.com/ardanlabs/garagesale/internal/platform/web"
+)
+
+// Authenticate validates the bearer token on every request (against the
+// local signing key or a registered OIDC provider's JWKS, whichever the
+// token's iss/kid identify) and attaches the resulting auth.Claims to the
+// context for handlers and downstream middleware to read.
+func Authenticate(authenticator *auth.Authenticator) web.Middleware {
+	m := func(after web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			header := r.Header.Get("Authorization")
+			parts := strings.Split(header, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				return web.WrapErrorWithStatus(errNoAuthHeader, http.StatusUnauthorized)
+			}
+
+			claims, err := authenticator.ParseClaims(parts[1])
+			if err != nil {
+				return web.WrapErrorWithStatus(err, http.StatusUnauthorized)
+			}
+
+			ctx = auth.ContextWithClaims(ctx, claims)
+
+			return after(ctx, w, r)
+		}
+		return h
+	}
+	return m
+}
+
+// HasRole rejects the request with a 403 unless the authenticated claims
+// include at least one of roles. It must run after Authenticate.
+func HasRole(roles ...string) web.Middleware {
+	m := func(after web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			claims, ok := ctx.Value(auth.Key).(auth.Claims)
+			if !ok || !claims.HasRole(roles...) {
+				return web.WrapErrorWithStatus(errForbidden, http.StatusForbidden)
+			}
+			return after(ctx, w, r)
+		}
+		return h
+	}
+	return m
+}
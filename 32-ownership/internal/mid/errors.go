@@ -0,0 +1,44 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ardanlabs/garagesale/internal/platform/log"
+	"github.com/ardanlabs/garagesale/internal/platform/web"
+	"github.com/pkg/errors"
+)
+
+var (
+	errNoAuthHeader = errors.New("missing or malformed Authorization header")
+	errForbidden    = errors.New("caller does not have the required role")
+	errReadOnly     = errors.New("service is in read-only mode")
+)
+
+// Errors runs just inside Metrics and Trace: it wraps everything downstream
+// (Authenticate, Logger, per-route middleware, the handler itself) and is
+// the only place a Handler's returned error is translated
+// into an HTTP response, via web.RespondError. Doing the translation here
+// instead of in App.Handle means Values.StatusCode is already populated by
+// the time control returns to Metrics and Trace, so those can report the
+// real status instead of the zero value. It always returns nil - so
+// App.Handle's own fallback doesn't write a second response for the same
+// request - recording the original error on Values.Err first so Metrics
+// and Trace, which can no longer see it via the return value, can still
+// observe it.
+func Errors() web.Middleware {
+	m := func(before web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			if err := before(ctx, w, r); err != nil {
+				if v, ok := ctx.Value(web.KeyValues).(*web.Values); ok {
+					v.Err = err
+				}
+				log.FromContext(ctx).Errorf("handling request: %s", err)
+				web.RespondError(ctx, w, err)
+			}
+			return nil
+		}
+		return h
+	}
+	return m
+}
@@ -0,0 +1,63 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ardanlabs/garagesale/internal/platform/auth"
+	"github.com/ardanlabs/garagesale/internal/platform/log"
+	"github.com/ardanlabs/garagesale/internal/platform/web"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// Logger attaches a request-scoped *log.Logger to the context (tagged with
+// a generated request id and the caller's user id) and emits one structured
+// record per request with method, path, latency and response status. It
+// must run inside Authenticate so claims are already on ctx by the time it
+// runs; requests rejected by Authenticate itself are logged by mid.Errors
+// instead.
+func Logger(logger *log.Logger) web.Middleware {
+	m := func(before web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			start := time.Now()
+
+			reqLogger := logger.WithFields(map[string]interface{}{
+				"request_id": uuid.New().String(),
+				"method":     r.Method,
+				"path":       r.URL.Path,
+			})
+
+			if claims, ok := ctx.Value(auth.Key).(auth.Claims); ok {
+				reqLogger = reqLogger.WithFields(map[string]interface{}{"user_id": claims.Subject})
+			}
+
+			ctx = log.WithContext(ctx, reqLogger)
+
+			err := before(ctx, w, r)
+
+			statusCode := http.StatusOK
+			if v, ok := ctx.Value(web.KeyValues).(*web.Values); ok && v.StatusCode != 0 {
+				statusCode = v.StatusCode
+			}
+			// mid.Errors writes error responses only after this returns, so
+			// derive the status it will send the same way RespondError does.
+			if err != nil {
+				statusCode = http.StatusInternalServerError
+				if webErr, ok := errors.Cause(err).(*web.Error); ok {
+					statusCode = webErr.Status
+				}
+			}
+
+			reqLogger.WithFields(map[string]interface{}{
+				"status":  statusCode,
+				"latency": time.Since(start).String(),
+			}).Infof("request complete")
+
+			return err
+		}
+		return h
+	}
+	return m
+}
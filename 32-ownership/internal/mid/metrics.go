@@ -0,0 +1,62 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ardanlabs/garagesale/internal/platform/metrics"
+	"github.com/ardanlabs/garagesale/internal/platform/web"
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+)
+
+// Metrics records the Prometheus request counter and latency histogram for
+// every request, labeled by the route's pattern (e.g. "/v1/products/{id}")
+// rather than the raw path so the label set stays bounded regardless of how
+// many distinct IDs get requested. It counts DBErrors only for errors that
+// aren't a *web.Error: those are the plain errors.Wrap(err, ...) calls the
+// products service layer makes on unexpected failures, as opposed to the
+// client-facing errors (bad input, not found, forbidden, read-only) that
+// handlers tag with web.WrapErrorWithStatus. Request-body decoding failures
+// are also returned untagged, so they are counted as DBErrors too. mid.Errors runs inside this
+// middleware and always returns nil, so the request's error (if any) is
+// read off Values.Err rather than the return value.
+func Metrics() web.Middleware {
+	m := func(before web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			start := time.Now()
+
+			err := before(ctx, w, r)
+
+			status := http.StatusOK
+			reqErr := err
+			if v, ok := ctx.Value(web.KeyValues).(*web.Values); ok {
+				if v.StatusCode != 0 {
+					status = v.StatusCode
+				}
+				if v.Err != nil {
+					reqErr = v.Err
+				}
+			}
+			if reqErr != nil {
+				if _, ok := errors.Cause(reqErr).(*web.Error); !ok {
+					metrics.DBErrors.Inc()
+				}
+			}
+
+			pattern := chi.RouteContext(r.Context()).RoutePattern()
+			if pattern == "" {
+				pattern = r.URL.Path
+			}
+
+			metrics.RequestCount.WithLabelValues(r.Method, pattern, strconv.Itoa(status)).Inc()
+			metrics.RequestLatency.WithLabelValues(r.Method, pattern).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+		return h
+	}
+	return m
+}
@@ -0,0 +1,32 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ardanlabs/garagesale/internal/platform/readonly"
+	"github.com/ardanlabs/garagesale/internal/platform/web"
+)
+
+// ReadOnly rejects any request other than GET/HEAD/OPTIONS with a 503 and a
+// Retry-After header while read-only mode is enabled. It is meant to be
+// applied only to routes that mutate state (Create/Update/Delete/AddSale);
+// List/Get/ListSales keep serving normally.
+func ReadOnly() web.Middleware {
+	m := func(after web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			if readonly.Enabled() {
+				switch r.Method {
+				case http.MethodGet, http.MethodHead, http.MethodOptions:
+				default:
+					w.Header().Set("Retry-After", "30")
+					return web.WrapErrorWithStatus(errReadOnly, http.StatusServiceUnavailable)
+				}
+			}
+
+			return after(ctx, w, r)
+		}
+		return h
+	}
+	return m
+}
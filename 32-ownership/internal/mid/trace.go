@@ -0,0 +1,48 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ardanlabs/garagesale/internal/platform/web"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// Trace starts a server span for every request, extracting a parent
+// SpanContext from incoming trace headers when present, and attaches the
+// span to the request context so handlers can add their own tags/events
+// (product id, claims subject, etc) via opentracing.SpanFromContext.
+// mid.Errors runs inside this middleware and always returns nil, so the
+// request's error (if any) is read off Values.Err rather than the return
+// value.
+func Trace() web.Middleware {
+	m := func(before web.Handler) web.Handler {
+		h := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			tracer := opentracing.GlobalTracer()
+
+			wireCtx, _ := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
+
+			span := tracer.StartSpan(r.Method+" "+r.URL.Path, ext.RPCServerOption(wireCtx))
+			defer span.Finish()
+
+			ext.HTTPMethod.Set(span, r.Method)
+			ext.HTTPUrl.Set(span, r.URL.String())
+
+			ctx = opentracing.ContextWithSpan(ctx, span)
+
+			err := before(ctx, w, r)
+
+			if v, ok := ctx.Value(web.KeyValues).(*web.Values); ok {
+				if err != nil || v.Err != nil {
+					ext.Error.Set(span, true)
+				}
+				ext.HTTPStatusCode.Set(span, uint16(v.StatusCode))
+			}
+
+			return err
+		}
+		return h
+	}
+	return m
+}
@@ -0,0 +1,172 @@
+// Package auth provides authentication and authorization support. Bearer
+// tokens are RSA-signed JWTs whose claims are decoded into Claims.
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+// ctxKey represents the type of value for the context key.
+type ctxKey int
+
+// Key is used to store/retrieve a Claims value from a context.Context.
+const Key ctxKey = 1
+
+// These are the expected values for Claims.Roles.
+const (
+	RoleAdmin = "ADMIN"
+	RoleUser  = "USER"
+)
+
+// Claims represents the authorization claims transmitted via a JWT.
+type Claims struct {
+	jwt.StandardClaims
+	Roles []string `json:"roles"`
+}
+
+// HasRole returns true if the claims has at least one of the provided roles.
+func (c Claims) HasRole(roles ...string) bool {
+	for _, has := range c.Roles {
+		for _, want := range roles {
+			if has == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Authenticator is used to authenticate clients by parsing and validating
+// bearer tokens, and to generate new tokens for clients that have already
+// authenticated by other means (username/password, etc). Tokens issued
+// locally are validated against privateKey/keyID; tokens from a configured
+// external OIDC provider are validated against that provider's JWKS,
+// selected by the token's iss claim.
+type Authenticator struct {
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+	algorithm  string
+	keyID      string
+
+	oidcProviders map[string]*oidcProvider // keyed by issuer
+}
+
+// NewAuthenticator creates an *Authenticator for generating and validating
+// locally-issued tokens signed with privateKey.
+func NewAuthenticator(privateKey *rsa.PrivateKey, keyID, algorithm string) (*Authenticator, error) {
+	if jwt.GetSigningMethod(algorithm) == nil {
+		return nil, errors.Errorf("unknown algorithm %q", algorithm)
+	}
+
+	return &Authenticator{
+		privateKey:    privateKey,
+		publicKey:     &privateKey.PublicKey,
+		algorithm:     algorithm,
+		keyID:         keyID,
+		oidcProviders: map[string]*oidcProvider{},
+	}, nil
+}
+
+// AddOIDCProvider discovers cfg's provider metadata and JWKS and registers
+// it so ParseClaims will accept tokens whose iss claim matches it.
+func (a *Authenticator) AddOIDCProvider(cfg OIDCConfig) error {
+	p, err := discoverOIDC(cfg)
+	if err != nil {
+		return errors.Wrapf(err, "discovering oidc provider %q", cfg.IssuerURL)
+	}
+
+	if a.oidcProviders == nil {
+		a.oidcProviders = map[string]*oidcProvider{}
+	}
+	a.oidcProviders[cfg.IssuerURL] = p
+
+	return nil
+}
+
+// GenerateToken generates a signed JWT token string representing claims.
+func (a *Authenticator) GenerateToken(claims Claims) (string, error) {
+	method := jwt.GetSigningMethod(a.algorithm)
+
+	tkn := jwt.NewWithClaims(method, claims)
+	tkn.Header["kid"] = a.keyID
+
+	str, err := tkn.SignedString(a.privateKey)
+	if err != nil {
+		return "", errors.Wrap(err, "signing token")
+	}
+
+	return str, nil
+}
+
+// ParseClaims recreates the Claims that were used to generate a token. A
+// token whose iss claim matches a registered OIDC provider is validated
+// against that provider's JWKS, with roles mapped from the provider's
+// configured RolesClaim path; any other token (including one with no iss)
+// is validated against the local key.
+func (a *Authenticator) ParseClaims(tknStr string) (Claims, error) {
+	_, iss, raw, err := unverifiedHeaderAndClaims(tknStr)
+	if err != nil {
+		return Claims{}, errors.Wrap(err, "inspecting token")
+	}
+
+	if provider, ok := a.oidcProviders[iss]; ok {
+		return a.parseOIDCClaims(tknStr, provider, raw)
+	}
+
+	var claims Claims
+	tkn, err := jwt.ParseWithClaims(tknStr, &claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return a.publicKey, nil
+	})
+	if err != nil {
+		return Claims{}, errors.Wrap(err, "parsing token")
+	}
+
+	if !tkn.Valid {
+		return Claims{}, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
+
+// parseOIDCClaims validates tknStr against provider's JWKS (matching the
+// token's kid) and maps its claims into our own Claims type.
+func (a *Authenticator) parseOIDCClaims(tknStr string, provider *oidcProvider, raw map[string]interface{}) (Claims, error) {
+	var claims jwt.MapClaims
+	tkn, err := jwt.Parse(tknStr, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return provider.publicKey(kid)
+	})
+	if err != nil {
+		return Claims{}, errors.Wrap(err, "parsing oidc token")
+	}
+	claims = tkn.Claims.(jwt.MapClaims)
+
+	if !tkn.Valid {
+		return Claims{}, errors.New("invalid oidc token")
+	}
+
+	subject, _ := claims["sub"].(string)
+
+	return Claims{
+		StandardClaims: jwt.StandardClaims{
+			Subject: subject,
+			Issuer:  provider.cfg.IssuerURL,
+		},
+		Roles: provider.rolesFromClaims(raw),
+	}, nil
+}
+
+// ContextWithClaims returns a new context carrying claims.
+func ContextWithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, Key, claims)
+}
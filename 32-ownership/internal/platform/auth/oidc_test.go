@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRolesFromClaimsDottedPath(t *testing.T) {
+	p := &oidcProvider{cfg: OIDCConfig{RolesClaim: "realm_access.roles"}}
+
+	raw := map[string]interface{}{
+		"sub": "user-1",
+		"realm_access": map[string]interface{}{
+			"roles": []interface{}{"ADMIN", "USER"},
+		},
+	}
+
+	got := p.rolesFromClaims(raw)
+	want := []string{"ADMIN", "USER"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("rolesFromClaims() = %v; want %v", got, want)
+	}
+}
+
+func TestRolesFromClaimsMissingPath(t *testing.T) {
+	p := &oidcProvider{cfg: OIDCConfig{RolesClaim: "groups"}}
+
+	if got := p.rolesFromClaims(map[string]interface{}{"sub": "user-1"}); got != nil {
+		t.Fatalf("rolesFromClaims() = %v; want nil", got)
+	}
+}
+
+func newTestJWK(t *testing.T, kid string, key *rsa.PublicKey) jwk {
+	t.Helper()
+
+	eb := big.NewInt(int64(key.E)).Bytes()
+
+	return jwk{
+		Kid: kid,
+		Kty: "RSA",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(eb),
+	}
+}
+
+func TestDiscoverOIDCFetchesJWKS(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %s", err)
+	}
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{Issuer: srv.URL, JWKSURI: srv.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jwkSet{Keys: []jwk{newTestJWK(t, "kid-1", &priv.PublicKey)}})
+	})
+
+	p, err := discoverOIDC(OIDCConfig{IssuerURL: srv.URL})
+	if err != nil {
+		t.Fatalf("discoverOIDC: %s", err)
+	}
+
+	key, err := p.publicKey("kid-1")
+	if err != nil {
+		t.Fatalf("publicKey(kid-1): %s", err)
+	}
+	if key.N.Cmp(priv.PublicKey.N) != 0 {
+		t.Fatal("publicKey(kid-1) returned the wrong modulus")
+	}
+
+	if _, err := p.publicKey("unknown-kid"); err == nil {
+		t.Fatal("expected an error for a kid absent from the JWKS even after a refresh")
+	}
+}
@@ -0,0 +1,45 @@
+// Package database centralizes the Postgres connection setup shared by
+// cmd/sales-api and cmd/sales-admin, so both binaries build the same DSN
+// from the same config shape.
+package database
+
+import (
+	"net/url"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// Config holds the parameters needed to connect to Postgres.
+//
+// NOTE: We don't pass in a connection string b/c our application may
+//       assume certain parameters are set.
+type Config struct {
+	User     string `default:"postgres"`
+	Password string `default:"postgres" json:"-"` // Prevent the marshalling of secrets.
+	Host     string `default:"localhost"`
+	Name     string `default:"postgres"`
+
+	DisableTLS bool `default:"false" envconfig:"disable_tls"`
+}
+
+// Open connects to the Postgres database described by cfg.
+func Open(cfg Config) (*sqlx.DB, error) {
+	sslMode := "require"
+	if cfg.DisableTLS {
+		sslMode = "disable"
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(cfg.User, cfg.Password),
+		Host:   cfg.Host,
+		Path:   cfg.Name,
+		RawQuery: (url.Values{
+			"sslmode":  []string{sslMode},
+			"timezone": []string{"utc"},
+		}).Encode(),
+	}
+
+	return sqlx.Connect("postgres", u.String())
+}
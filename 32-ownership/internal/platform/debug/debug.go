@@ -0,0 +1,66 @@
+// Package debug builds the handler served by the admin HTTP server: health
+// probes for a load balancer plus expvar/pprof/Prometheus endpoints for
+// operators.
+package debug
+
+import (
+	"context"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Mux constructs the admin http.Handler. ready is read on every readiness
+// check and is expected to be flipped to 0 by the caller as soon as
+// shutdown begins, so the probe starts failing before the main server
+// stops accepting connections.
+func Mux(db *sqlx.DB, ready *int32) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/liveness", liveness)
+	mux.HandleFunc("/readiness", readiness(db, ready))
+
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return mux
+}
+
+// liveness reports 200 as long as the process is able to answer at all.
+func liveness(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// readiness reports 503 while draining or if the database can't be
+// reached within a bounded timeout, and 200 otherwise.
+func readiness(db *sqlx.DB, ready *int32) http.HandlerFunc {
+	const pingTimeout = time.Second
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(ready) == 0 {
+			http.Error(w, "draining", http.StatusServiceUnavailable)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), pingTimeout)
+		defer cancel()
+
+		if err := db.PingContext(ctx); err != nil {
+			http.Error(w, "database unreachable", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
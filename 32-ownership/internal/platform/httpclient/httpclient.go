@@ -0,0 +1,45 @@
+// Package httpclient provides an http.Client whose RoundTripper injects the
+// active span's trace headers into outgoing requests, so spans started by
+// mid.Trace continue across service calls.
+package httpclient
+
+import (
+	"net/http"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// New returns an *http.Client that propagates the opentracing span found on
+// each outgoing request's context, if any.
+func New() *http.Client {
+	return &http.Client{
+		Transport: &tracingTransport{base: http.DefaultTransport},
+	}
+}
+
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	span := opentracing.SpanFromContext(req.Context())
+	if span == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	span.SetTag("http.method", req.Method)
+	span.SetTag("http.url", req.URL.String())
+
+	carrier := opentracing.HTTPHeadersCarrier(req.Header)
+	span.Tracer().Inject(span.Context(), opentracing.HTTPHeaders, carrier)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.SetTag("error", true)
+		return resp, err
+	}
+
+	span.SetTag("http.status_code", resp.StatusCode)
+
+	return resp, nil
+}
@@ -0,0 +1,85 @@
+// Package log provides a structured, request-scoped logger built on top of
+// logrus. A single *Logger is created at startup and a copy carrying
+// per-request fields is attached to each request's context by web
+// middleware, so handlers can log without threading a logger through every
+// function call.
+package log
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Logger wraps a logrus entry so callers get a consistent API regardless of
+// whether they are logging from main (no fields yet) or from a handler
+// (request-id, user-id, etc. already attached).
+type Logger struct {
+	entry *logrus.Entry
+}
+
+// Config controls how the root Logger is constructed.
+type Config struct {
+	Level  string `default:"info"`
+	Format string `default:"json"` // "json" or "text"
+}
+
+// New builds the root Logger from cfg, writing to w.
+func New(cfg Config, w io.Writer) (*Logger, error) {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	base := logrus.New()
+	base.SetOutput(w)
+	base.SetLevel(level)
+
+	switch cfg.Format {
+	case "text":
+		base.SetFormatter(&logrus.TextFormatter{})
+	default:
+		base.SetFormatter(&logrus.JSONFormatter{})
+	}
+
+	return &Logger{entry: logrus.NewEntry(base)}, nil
+}
+
+// NewStdLogger is a convenience for code paths (tests, CLI tools) that just
+// want a reasonable default logger writing to stdout.
+func NewStdLogger() *Logger {
+	l, _ := New(Config{Level: "info", Format: "json"}, os.Stdout)
+	return l
+}
+
+// WithFields returns a Logger carrying the given fields in addition to any
+// the receiver already has.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	return &Logger{entry: l.entry.WithFields(fields)}
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) { l.entry.Debugf(format, args...) }
+func (l *Logger) Infof(format string, args ...interface{})  { l.entry.Infof(format, args...) }
+func (l *Logger) Warnf(format string, args ...interface{})  { l.entry.Warnf(format, args...) }
+func (l *Logger) Errorf(format string, args ...interface{}) { l.entry.Errorf(format, args...) }
+
+type ctxKey int
+
+const loggerKey ctxKey = 1
+
+// WithContext returns a new context carrying logger, for middleware to call
+// once per request after adding request-id/user-id fields.
+func WithContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext returns the Logger attached to ctx, or a bare fallback logger
+// if none was attached (e.g. in a background job or test).
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey).(*Logger); ok {
+		return l
+	}
+	return NewStdLogger()
+}
@@ -0,0 +1,31 @@
+// Package metrics holds the Prometheus collectors shared across the
+// service, so both the request middleware that records them and the debug
+// server that exposes /metrics refer to the same instances.
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// RequestCount counts every request handled, labeled by method, path
+	// and response status.
+	RequestCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sales_api_requests_total",
+		Help: "Total number of HTTP requests processed.",
+	}, []string{"method", "path", "status"})
+
+	// RequestLatency tracks the distribution of request handling time.
+	RequestLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "sales_api_request_duration_seconds",
+		Help: "Latency distribution of HTTP requests.",
+	}, []string{"method", "path"})
+
+	// DBErrors counts requests that failed because of the database layer.
+	DBErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "sales_api_db_errors_total",
+		Help: "Total number of database errors encountered while serving requests.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(RequestCount, RequestLatency, DBErrors)
+}
@@ -0,0 +1,36 @@
+// Package readonly holds the process-wide read-only flag. It is read on
+// every write request by mid.ReadOnly and flipped by the admin endpoint
+// (or at startup from config), so it needs to be lock-free on the hot path.
+package readonly
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var enabled int32
+
+var gauge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "sales_api_readonly_enabled",
+	Help: "1 if the service is currently rejecting writes, 0 otherwise.",
+})
+
+func init() {
+	prometheus.MustRegister(gauge)
+}
+
+// SetEnabled flips read-only mode on or off.
+func SetEnabled(v bool) {
+	var n int32
+	if v {
+		n = 1
+	}
+	atomic.StoreInt32(&enabled, n)
+	gauge.Set(float64(n))
+}
+
+// Enabled reports whether read-only mode is currently active.
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
@@ -0,0 +1,21 @@
+package readonly
+
+import "testing"
+
+func TestSetEnabled(t *testing.T) {
+	defer SetEnabled(false)
+
+	if Enabled() {
+		t.Fatal("expected read-only mode to start disabled")
+	}
+
+	SetEnabled(true)
+	if !Enabled() {
+		t.Fatal("expected read-only mode to be enabled")
+	}
+
+	SetEnabled(false)
+	if Enabled() {
+		t.Fatal("expected read-only mode to be disabled")
+	}
+}
@@ -0,0 +1,44 @@
+// Package trace wires up distributed tracing for the service. It builds an
+// opentracing.Tracer backed by Jaeger and registers it as the process-wide
+// global tracer so that mid.Trace and the instrumented http.Client in
+// internal/platform/httpclient can both reach it via opentracing.GlobalTracer().
+package trace
+
+import (
+	"io"
+
+	"github.com/opentracing/opentracing-go"
+	jaegercfg "github.com/uber/jaeger-client-go/config"
+)
+
+// Config controls how the tracer is constructed.
+type Config struct {
+	ServiceName  string  `default:"sales-api"`
+	Endpoint     string  `default:"http://localhost:14268/api/traces" envconfig:"endpoint"`
+	SampleRate   float64 `default:"1" envconfig:"sample_rate"`
+}
+
+// New builds a Tracer from cfg, registers it as the global tracer, and
+// returns a Closer that must be called (e.g. via defer in main) to flush
+// any buffered spans on shutdown.
+func New(cfg Config) (opentracing.Tracer, io.Closer, error) {
+	jcfg := jaegercfg.Configuration{
+		ServiceName: cfg.ServiceName,
+		Sampler: &jaegercfg.SamplerConfig{
+			Type:  "probabilistic",
+			Param: cfg.SampleRate,
+		},
+		Reporter: &jaegercfg.ReporterConfig{
+			CollectorEndpoint: cfg.Endpoint,
+		},
+	}
+
+	tracer, closer, err := jcfg.NewTracer()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opentracing.SetGlobalTracer(tracer)
+
+	return tracer, closer, nil
+}
@@ -0,0 +1,174 @@
+// Package web contains a small framework extension that sits on top of
+// net/http and chi. It gives every handler a uniform signature and a place
+// to hang request-scoped values (trace id, timing, status code) that
+// middleware and handlers both need.
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+)
+
+// ctxKey represents the type of value for the context key.
+type ctxKey int
+
+// KeyValues is how request values are stored/retrieved from the context.
+const KeyValues ctxKey = 1
+
+// Values carries information about each request.
+type Values struct {
+	TraceID    string
+	Now        time.Time
+	StatusCode int
+
+	// Err is the error (if any) a Handler returned, recorded here by
+	// mid.Errors before it translates the error to a response and swallows
+	// it. mid.Errors must return nil so App.Handle's own fallback doesn't
+	// write a second response, which means the err returned to outer
+	// middleware (Metrics, Trace) is always nil; they read Err instead.
+	Err error
+}
+
+// Handler is the signature every application handler implements. Returning
+// an error lets a single piece of middleware (see mid.Errors) do all of
+// the error-to-HTTP-response translation in one place.
+type Handler func(ctx context.Context, w http.ResponseWriter, r *http.Request) error
+
+// Middleware is a function designed to run some code before and/or after
+// another Handler, returning a new Handler that wraps it.
+type Middleware func(Handler) Handler
+
+// App is the entrypoint into our application and what configures our
+// context object for each of our http handlers.
+type App struct {
+	mux *chi.Mux
+	mw  []Middleware
+}
+
+// NewApp creates an App value that handles a set of routes for the
+// application, with mw applied to every handler registered on it.
+func NewApp(mw ...Middleware) *App {
+	return &App{
+		mux: chi.NewRouter(),
+		mw:  mw,
+	}
+}
+
+// Handle associates a handler function with an HTTP method and pattern.
+// Route-specific middleware, if any, runs closest to the handler.
+func (a *App) Handle(method, pattern string, h Handler, mw ...Middleware) {
+	h = wrapMiddleware(mw, h)
+	h = wrapMiddleware(a.mw, h)
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), KeyValues, &Values{
+			Now: time.Now(),
+		})
+		r = r.WithContext(ctx)
+
+		if err := h(ctx, w, r); err != nil {
+			RespondError(ctx, w, err)
+		}
+	}
+
+	a.mux.MethodFunc(method, pattern, fn)
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// wrapMiddleware wraps a handler with the given middleware, running the
+// first entry in mw outermost.
+func wrapMiddleware(mw []Middleware, h Handler) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		if mw[i] != nil {
+			h = mw[i](h)
+		}
+	}
+	return h
+}
+
+// Respond marshals data as JSON and writes it to the response with the
+// given status code, recording the status on the request's Values so
+// downstream middleware (logging, metrics) can read it back.
+func Respond(ctx context.Context, w http.ResponseWriter, data interface{}, statusCode int) error {
+	if v, ok := ctx.Value(KeyValues).(*Values); ok {
+		v.StatusCode = statusCode
+	}
+
+	if statusCode == http.StatusNoContent {
+		w.WriteHeader(statusCode)
+		return nil
+	}
+
+	res, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "marshalling response")
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(res); err != nil {
+		return errors.Wrap(err, "writing response")
+	}
+
+	return nil
+}
+
+// RespondError knows how to turn an error returned by a Handler into an
+// HTTP response.
+func RespondError(ctx context.Context, w http.ResponseWriter, err error) {
+	if webErr, ok := errors.Cause(err).(*Error); ok {
+		Respond(ctx, w, ErrorResponse{Error: webErr.Error()}, webErr.Status)
+		return
+	}
+
+	Respond(ctx, w, ErrorResponse{Error: http.StatusText(http.StatusInternalServerError)}, http.StatusInternalServerError)
+}
+
+// Error is used to pass an error during the request through the
+// application, with web specific context (a status code) attached.
+type Error struct {
+	Err    error
+	Status int
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// WrapErrorWithStatus wraps an error with a status code, so the error
+// middleware knows which HTTP status to respond with.
+func WrapErrorWithStatus(err error, status int) error {
+	return &Error{Err: err, Status: status}
+}
+
+// ErrorResponse is the form used for API responses from failures in the
+// API.
+type ErrorResponse struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Decode reads the body of an HTTP request looking for a JSON document. The
+// body is decoded into the provided value.
+func Decode(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(v); err != nil {
+		if err == io.EOF {
+			return errors.New("request body is empty")
+		}
+		return errors.Wrap(err, "decoding request body")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Package products implements the service layer for products and their
+// sales: validation, persistence and the filtering/sorting/pagination logic
+// that backs the list endpoints in cmd/sales-api.
+package products
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// These are the expected errors from the functions in this package.
+var (
+	// ErrNotFound is used when a specific Product or Sale is requested but
+	// does not exist.
+	ErrNotFound = errors.New("product not found")
+
+	// ErrInvalidID is used when an ID is not in a valid form.
+	ErrInvalidID = errors.New("id provided was not a valid UUID")
+
+	// ErrForbidden is used when a claim does not have the necessary role to
+	// modify the requested product.
+	ErrForbidden = errors.New("caller does not have permission to modify this product")
+)
+
+// Product is an item sold in the store.
+type Product struct {
+	ID          string    `db:"product_id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Cost        int       `db:"cost" json:"cost"`
+	Quantity    int       `db:"quantity" json:"quantity"`
+	Sold        int       `db:"sold" json:"sold"`
+	Revenue     int       `db:"revenue" json:"revenue"`
+	UserID      string    `db:"user_id" json:"user_id"`
+	DateCreated time.Time `db:"date_created" json:"date_created"`
+	DateUpdated time.Time `db:"date_updated" json:"date_updated"`
+}
+
+// NewProduct is what is required from clients when adding a Product.
+type NewProduct struct {
+	Name     string `json:"name" validate:"required"`
+	Cost     int    `json:"cost" validate:"required,gte=0"`
+	Quantity int    `json:"quantity" validate:"required,gte=0"`
+}
+
+// UpdateProduct defines what information may be provided to modify an
+// existing Product. Fields left nil are left unchanged.
+type UpdateProduct struct {
+	Name     *string `json:"name"`
+	Cost     *int    `json:"cost" validate:"omitempty,gte=0"`
+	Quantity *int    `json:"quantity" validate:"omitempty,gte=0"`
+}
+
+// Sale represents a purchase of some quantity of a Product.
+type Sale struct {
+	ID          string    `db:"sale_id" json:"id"`
+	ProductID   string    `db:"product_id" json:"product_id"`
+	Quantity    int       `db:"quantity" json:"quantity"`
+	Paid        int       `db:"paid" json:"paid"`
+	DateCreated time.Time `db:"date_created" json:"date_created"`
+}
+
+// NewSale is what is required from clients when recording a Sale against a
+// Product.
+type NewSale struct {
+	Quantity int `json:"quantity" validate:"required,gte=1"`
+	Paid     int `json:"paid" validate:"required,gte=0"`
+}
+
+// ListParams carries the filtering, sorting and pagination input shared by
+// List and ListSales. Sort is the external (query-string) sort key; callers
+// are expected to have already validated it against an allowlist before
+// passing it in.
+type ListParams struct {
+	Limit         int
+	Sort          string
+	LastID        string
+	LastSortValue interface{}
+	Name          string
+	MinCost       *int
+	MaxCost       *int
+}
@@ -0,0 +1,179 @@
+package products
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ardanlabs/garagesale/internal/platform/auth"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// productColumns lists the sort keys List accepts and the SQL column each
+// maps to. Keeping this separate from (though aligned with) the handlers'
+// query-string whitelist means a caller can never interpolate arbitrary
+// input into the ORDER BY clause, even if the handler-level check were ever
+// removed or loosened.
+var productColumns = map[string]string{
+	"product_id":   "product_id",
+	"name":         "name",
+	"cost":         "cost",
+	"date_created": "date_created",
+}
+
+// List returns a page of products matching params, along with the total
+// number of products matching the filters (ignoring Limit/LastID).
+func List(ctx context.Context, db *sqlx.DB, params ListParams) ([]Product, int, error) {
+	sortColumn, ok := productColumns[params.Sort]
+	if !ok {
+		return nil, 0, errors.Errorf("invalid sort field %q", params.Sort)
+	}
+
+	filterWhere := "WHERE true"
+	var filterArgs []interface{}
+
+	if params.Name != "" {
+		filterArgs = append(filterArgs, "%"+params.Name+"%")
+		filterWhere += fmt.Sprintf(" AND name ILIKE $%d", len(filterArgs))
+	}
+	if params.MinCost != nil {
+		filterArgs = append(filterArgs, *params.MinCost)
+		filterWhere += fmt.Sprintf(" AND cost >= $%d", len(filterArgs))
+	}
+	if params.MaxCost != nil {
+		filterArgs = append(filterArgs, *params.MaxCost)
+		filterWhere += fmt.Sprintf(" AND cost <= $%d", len(filterArgs))
+	}
+
+	// total reflects the filters only, not the cursor, so it stays stable
+	// across a single pagination sequence instead of shrinking page by page.
+	var total int
+	countQ := "SELECT COUNT(*) FROM products " + filterWhere
+	if err := db.GetContext(ctx, &total, countQ, filterArgs...); err != nil {
+		return nil, 0, errors.Wrap(err, "counting products")
+	}
+
+	listWhere := filterWhere
+	listArgs := append([]interface{}{}, filterArgs...)
+	if params.LastID != "" {
+		listArgs = append(listArgs, params.LastSortValue)
+		sortArg := len(listArgs)
+		listArgs = append(listArgs, params.LastID)
+		idArg := len(listArgs)
+		// Keyset on (sortColumn, product_id) so a page change can never
+		// reorder the result set by a column ORDER BY isn't using.
+		listWhere += fmt.Sprintf(" AND (%s > $%d OR (%s = $%d AND product_id > $%d))",
+			sortColumn, sortArg, sortColumn, sortArg, idArg)
+	}
+
+	listArgs = append(listArgs, params.Limit)
+	listQ := fmt.Sprintf(
+		`SELECT product_id, name, cost, quantity, sold, revenue, user_id, date_created, date_updated
+		FROM products %s
+		ORDER BY %s, product_id
+		LIMIT $%d`, listWhere, sortColumn, len(listArgs))
+
+	list := []Product{}
+	if err := db.SelectContext(ctx, &list, listQ, listArgs...); err != nil {
+		return nil, 0, errors.Wrap(err, "selecting products")
+	}
+
+	return list, total, nil
+}
+
+// Get finds a single Product by ID.
+func Get(ctx context.Context, db *sqlx.DB, id string) (Product, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return Product{}, ErrInvalidID
+	}
+
+	const q = `SELECT product_id, name, cost, quantity, sold, revenue, user_id, date_created, date_updated
+		FROM products
+		WHERE product_id = $1`
+
+	var p Product
+	if err := db.GetContext(ctx, &p, q, id); err != nil {
+		if err == sql.ErrNoRows {
+			return Product{}, ErrNotFound
+		}
+		return Product{}, errors.Wrap(err, "selecting single product")
+	}
+
+	return p, nil
+}
+
+// Create adds a Product to the database, owned by claims.Subject.
+func Create(ctx context.Context, db *sqlx.DB, claims auth.Claims, np NewProduct, now time.Time) (Product, error) {
+	p := Product{
+		ID:          uuid.New().String(),
+		Name:        np.Name,
+		Cost:        np.Cost,
+		Quantity:    np.Quantity,
+		UserID:      claims.Subject,
+		DateCreated: now.UTC(),
+		DateUpdated: now.UTC(),
+	}
+
+	const q = `INSERT INTO products
+		(product_id, name, cost, quantity, sold, revenue, user_id, date_created, date_updated)
+		VALUES ($1, $2, $3, $4, 0, 0, $5, $6, $7)`
+
+	if _, err := db.ExecContext(ctx, q, p.ID, p.Name, p.Cost, p.Quantity, p.UserID, p.DateCreated, p.DateUpdated); err != nil {
+		return Product{}, errors.Wrap(err, "inserting product")
+	}
+
+	return p, nil
+}
+
+// Update modifies data about a Product. It will error if the specified ID is
+// invalid, the product does not exist, or claims does not have permission to
+// modify it.
+func Update(ctx context.Context, db *sqlx.DB, claims auth.Claims, id string, update UpdateProduct, now time.Time) error {
+	p, err := Get(ctx, db, id)
+	if err != nil {
+		return err
+	}
+
+	if !claims.HasRole(auth.RoleAdmin) && claims.Subject != p.UserID {
+		return ErrForbidden
+	}
+
+	if update.Name != nil {
+		p.Name = *update.Name
+	}
+	if update.Cost != nil {
+		p.Cost = *update.Cost
+	}
+	if update.Quantity != nil {
+		p.Quantity = *update.Quantity
+	}
+	p.DateUpdated = now.UTC()
+
+	const q = `UPDATE products SET
+		name = $2, cost = $3, quantity = $4, date_updated = $5
+		WHERE product_id = $1`
+
+	if _, err := db.ExecContext(ctx, q, p.ID, p.Name, p.Cost, p.Quantity, p.DateUpdated); err != nil {
+		return errors.Wrap(err, "updating product")
+	}
+
+	return nil
+}
+
+// Delete removes the Product identified by id from the database.
+func Delete(ctx context.Context, db *sqlx.DB, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrInvalidID
+	}
+
+	const q = `DELETE FROM products WHERE product_id = $1`
+
+	if _, err := db.ExecContext(ctx, q, id); err != nil {
+		return errors.Wrap(err, "deleting product")
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// saleColumns lists the sort keys ListSales accepts and the SQL column each
+// maps to, mirroring productColumns.
+var saleColumns = map[string]string{
+	"sale_id":      "sale_id",
+	"date_created": "date_created",
+}
+
+// AddSale records a Sale against productID and credits the sold quantity and
+// revenue onto the product.
+func AddSale(ctx context.Context, db *sqlx.DB, ns NewSale, productID string, now time.Time) (Sale, error) {
+	if _, err := uuid.Parse(productID); err != nil {
+		return Sale{}, ErrInvalidID
+	}
+
+	s := Sale{
+		ID:          uuid.New().String(),
+		ProductID:   productID,
+		Quantity:    ns.Quantity,
+		Paid:        ns.Paid,
+		DateCreated: now.UTC(),
+	}
+
+	const insertSale = `INSERT INTO sales (sale_id, product_id, quantity, paid, date_created)
+		VALUES ($1, $2, $3, $4, $5)`
+	if _, err := db.ExecContext(ctx, insertSale, s.ID, s.ProductID, s.Quantity, s.Paid, s.DateCreated); err != nil {
+		return Sale{}, errors.Wrap(err, "inserting sale")
+	}
+
+	const updateProduct = `UPDATE products SET sold = sold + $2, revenue = revenue + $3 WHERE product_id = $1`
+	if _, err := db.ExecContext(ctx, updateProduct, productID, s.Quantity, s.Paid); err != nil {
+		return Sale{}, errors.Wrap(err, "crediting product with new sale")
+	}
+
+	return s, nil
+}
+
+// ListSales returns a page of sales recorded against productID matching
+// params, along with the total number of sales matching the filters
+// (ignoring Limit/LastID).
+func ListSales(ctx context.Context, db *sqlx.DB, productID string, params ListParams) ([]Sale, int, error) {
+	if _, err := uuid.Parse(productID); err != nil {
+		return nil, 0, ErrInvalidID
+	}
+
+	sortColumn, ok := saleColumns[params.Sort]
+	if !ok {
+		return nil, 0, errors.Errorf("invalid sort field %q", params.Sort)
+	}
+
+	filterWhere := "WHERE product_id = $1"
+	filterArgs := []interface{}{productID}
+
+	// total reflects the filter only, not the cursor, so it stays stable
+	// across a single pagination sequence instead of shrinking page by page.
+	var total int
+	countQ := "SELECT COUNT(*) FROM sales " + filterWhere
+	if err := db.GetContext(ctx, &total, countQ, filterArgs...); err != nil {
+		return nil, 0, errors.Wrap(err, "counting sales")
+	}
+
+	listWhere := filterWhere
+	listArgs := append([]interface{}{}, filterArgs...)
+	if params.LastID != "" {
+		listArgs = append(listArgs, params.LastSortValue)
+		sortArg := len(listArgs)
+		listArgs = append(listArgs, params.LastID)
+		idArg := len(listArgs)
+		// Keyset on (sortColumn, sale_id) so a page change can never
+		// reorder the result set by a column ORDER BY isn't using.
+		listWhere += fmt.Sprintf(" AND (%s > $%d OR (%s = $%d AND sale_id > $%d))",
+			sortColumn, sortArg, sortColumn, sortArg, idArg)
+	}
+
+	listArgs = append(listArgs, params.Limit)
+	listQ := fmt.Sprintf(
+		`SELECT sale_id, product_id, quantity, paid, date_created
+		FROM sales %s
+		ORDER BY %s, sale_id
+		LIMIT $%d`, listWhere, sortColumn, len(listArgs))
+
+	list := []Sale{}
+	if err := db.SelectContext(ctx, &list, listQ, listArgs...); err != nil {
+		return nil, 0, errors.Wrap(err, "selecting sales")
+	}
+
+	return list, total, nil
+}
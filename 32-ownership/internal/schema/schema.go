@@ -0,0 +1,44 @@
+// Package schema owns the database schema for the sales service: the DDL
+// applied by "sales-admin migrate" and the fixture data applied by
+// "sales-admin seed".
+package schema
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+const ddl = `
+CREATE TABLE IF NOT EXISTS products (
+	product_id   UUID         NOT NULL,
+	name         TEXT         NOT NULL,
+	cost         INT          NOT NULL,
+	quantity     INT          NOT NULL,
+	sold         INT          NOT NULL DEFAULT 0,
+	revenue      INT          NOT NULL DEFAULT 0,
+	user_id      UUID         NOT NULL,
+	date_created TIMESTAMP    NOT NULL,
+	date_updated TIMESTAMP    NOT NULL,
+
+	PRIMARY KEY (product_id)
+);
+
+CREATE TABLE IF NOT EXISTS sales (
+	sale_id      UUID         NOT NULL,
+	product_id   UUID         NOT NULL REFERENCES products(product_id),
+	quantity     INT          NOT NULL,
+	paid         INT          NOT NULL,
+	date_created TIMESTAMP    NOT NULL,
+
+	PRIMARY KEY (sale_id)
+);
+`
+
+// Migrate brings the database schema up to date by creating the products
+// and sales tables if they don't already exist.
+func Migrate(db *sqlx.DB) error {
+	if _, err := db.Exec(ddl); err != nil {
+		return errors.Wrap(err, "executing schema ddl")
+	}
+	return nil
+}
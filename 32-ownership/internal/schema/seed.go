@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+const seedData = `
+INSERT INTO products (product_id, name, cost, quantity, sold, revenue, user_id, date_created, date_updated) VALUES
+	('a2b0639f-2cc6-44b8-b97b-15d69dbb511e', 'Comic Books', 50, 42, 7, 350, '5cf37266-3473-4006-984f-9325122678b7', NOW(), NOW()),
+	('72f8b983-3eb4-48db-9ed0-e45cc6bd716b', 'McDonalds Toys', 75, 120, 3, 225, '5cf37266-3473-4006-984f-9325122678b7', NOW(), NOW())
+ON CONFLICT DO NOTHING;
+
+INSERT INTO sales (sale_id, product_id, quantity, paid, date_created) VALUES
+	('98b6d4b8-f04b-4c79-8c2e-a0aef46854b7', 'a2b0639f-2cc6-44b8-b97b-15d69dbb511e', 2, 100, NOW()),
+	('85f6fb09-eb05-4874-ae39-82d1a30fe0d7', 'a2b0639f-2cc6-44b8-b97b-15d69dbb511e', 5, 250, NOW()),
+	('a235be9e-ab5d-44e6-a987-fa1c749264c7', '72f8b983-3eb4-48db-9ed0-e45cc6bd716b', 3, 225, NOW())
+ON CONFLICT DO NOTHING;
+`
+
+// Seed inserts a small, fixed set of products and sales useful for local
+// development and manual testing against a fresh database.
+func Seed(db *sqlx.DB) error {
+	if _, err := db.Exec(seedData); err != nil {
+		return errors.Wrap(err, "executing seed data")
+	}
+	return nil
+}